@@ -11,10 +11,20 @@ import (
 
 // GCPHandler is a slog.Handler that formats logs for GCP Cloud Logging
 type GCPHandler struct {
-	w     io.Writer
-	level slog.Level
-	attrs []slog.Attr
-	group string
+	w      io.Writer
+	level  slog.Level
+	attrs  []attrGroup
+	groups []string
+}
+
+// attrGroup is a batch of attrs added via WithAttrs, tagged with the group
+// path that was open on the handler at the time (i.e. the WithGroup calls
+// preceding it). This lets Handle place each batch at the correct nested
+// path within Context even though groups and attrs are added by separate,
+// interleaved calls.
+type attrGroup struct {
+	groups []string
+	attrs  []slog.Attr
 }
 
 // gcpLogEntry represents a GCP Cloud Logging compatible log entry
@@ -23,6 +33,9 @@ type gcpLogEntry struct {
 	Message        string                 `json:"message"`
 	Timestamp      string                 `json:"timestamp"`
 	SourceLocation *sourceLocation        `json:"logging.googleapis.com/sourceLocation,omitempty"`
+	Trace          string                 `json:"logging.googleapis.com/trace,omitempty"`
+	SpanID         string                 `json:"logging.googleapis.com/spanId,omitempty"`
+	TraceSampled   bool                   `json:"logging.googleapis.com/trace_sampled,omitempty"`
 	Context        map[string]interface{} `json:"context"`
 	Extra          map[string]interface{} `json:"extra"`
 }
@@ -38,17 +51,54 @@ func NewGCPHandler(w io.Writer, level slog.Level) *GCPHandler {
 	return &GCPHandler{
 		w:     w,
 		level: level,
-		attrs: make([]slog.Attr, 0),
 	}
 }
 
 // Enabled reports whether the handler handles records at the given level
 func (h *GCPHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	// when vmodule patterns are configured, the final accept/reject
+	// decision needs the caller's frame, which is only available once
+	// the record has been created with a PC. Defer to Handle.
+	if vmoduleActive() {
+		return true
+	}
 	return level >= h.level
 }
 
 // Handle formats and writes a log record
 func (h *GCPHandler) Handle(ctx context.Context, r slog.Record) error {
+	// resolve the caller's frame if available and either the source location
+	// will be logged (level is debug or error) or a vmodule pattern needs the
+	// frame to decide whether to drop the record
+	shouldLogSource := r.Level == slog.LevelDebug || r.Level == slog.LevelError
+	vmoduleOn := vmoduleActive()
+
+	var frame runtime.Frame
+	var haveFrame bool
+	if r.PC != 0 && (shouldLogSource || vmoduleOn) {
+		fs := runtime.CallersFrames([]uintptr{r.PC})
+		frame, _ = fs.Next()
+		haveFrame = true
+	}
+
+	if vmoduleOn {
+		var level slog.Level
+		var matched bool
+		if haveFrame {
+			level, matched = matchVModule(frame)
+		}
+		// a pattern match overrides h.level for this frame; otherwise fall
+		// back to the handler's base level so unrelated packages aren't
+		// silently let through just because some vmodule rule is set
+		if matched {
+			if r.Level < level {
+				return nil
+			}
+		} else if r.Level < h.level {
+			return nil
+		}
+	}
+
 	// Convert slog level to GCP severity
 	severity := levelToGCPSeverity(r.Level)
 
@@ -60,29 +110,41 @@ func (h *GCPHandler) Handle(ctx context.Context, r slog.Record) error {
 		Extra:     globalExtraFields,
 	}
 
-	// add source location if available and level is debug or error
-	shouldLogSource := r.Level == slog.LevelDebug || r.Level == slog.LevelError
-	if r.PC != 0 && shouldLogSource {
-		fs := runtime.CallersFrames([]uintptr{r.PC})
-		f, _ := fs.Next()
+	if haveFrame && shouldLogSource {
 		entry.SourceLocation = &sourceLocation{
-			File:     f.File,
-			Line:     f.Line,
-			Function: f.Function,
+			File:     frame.File,
+			Line:     frame.Line,
+			Function: frame.Function,
 		}
 	}
 
-	// Add handler's preset attributes
-	for _, attr := range h.attrs {
-		addAttrToFields(entry.Context, attr)
+	// lift trace correlation out of ctx into the dedicated GCP fields
+	if tc, ok := traceFromContext(ctx); ok {
+		entry.Trace = tc.Trace
+		entry.SpanID = tc.SpanID
+		entry.TraceSampled = tc.Sampled
 	}
 
-	// Add record's attributes
+	// Add handler's preset attributes, each placed at the group path that
+	// was open on the handler when it was added via WithAttrs
+	for _, ag := range h.attrs {
+		dst := nestedContext(entry.Context, ag.groups)
+		for _, attr := range ag.attrs {
+			addAttrToFields(dst, attr)
+		}
+	}
+
+	// Add record's attributes, nested under the handler's current group path
+	dst := nestedContext(entry.Context, h.groups)
 	r.Attrs(func(attr slog.Attr) bool {
-		addAttrToFields(entry.Context, attr)
+		addAttrToFields(dst, attr)
 		return true
 	})
 
+	// drop any group that ended up with nothing in it (e.g. WithGroup with
+	// no attrs, or an empty slog.Group), matching slog.NewJSONHandler
+	pruneEmptyGroups(entry.Context)
+
 	// Marshal to JSON and write
 	b, err := json.Marshal(entry)
 	if err != nil {
@@ -93,42 +155,93 @@ func (h *GCPHandler) Handle(ctx context.Context, r slog.Record) error {
 	return err
 }
 
-// WithAttrs returns a new handler with additional attributes
+// WithAttrs returns a new handler with additional attributes, tagged with
+// the handler's current group path so Handle can nest them correctly.
 func (h *GCPHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	newAttrs := make([]slog.Attr, len(h.attrs)+len(attrs))
+	newAttrs := make([]attrGroup, len(h.attrs)+1)
 	copy(newAttrs, h.attrs)
-	copy(newAttrs[len(h.attrs):], attrs)
+	newAttrs[len(h.attrs)] = attrGroup{groups: h.groups, attrs: attrs}
 
 	return &GCPHandler{
-		w:     h.w,
-		level: h.level,
-		attrs: newAttrs,
-		group: h.group,
+		w:      h.w,
+		level:  h.level,
+		attrs:  newAttrs,
+		groups: h.groups,
 	}
 }
 
-// WithGroup returns a new handler with a group prefix
+// WithGroup returns a new handler with name pushed onto the group stack, so
+// that attrs and record fields added afterward nest under it.
 func (h *GCPHandler) WithGroup(name string) slog.Handler {
+	newGroups := make([]string, len(h.groups)+1)
+	copy(newGroups, h.groups)
+	newGroups[len(h.groups)] = name
+
 	return &GCPHandler{
-		w:     h.w,
-		level: h.level,
-		attrs: h.attrs,
-		group: name,
+		w:      h.w,
+		level:  h.level,
+		attrs:  h.attrs,
+		groups: newGroups,
+	}
+}
+
+// nestedContext walks (creating as needed) the nested maps within fields
+// described by groups, and returns the innermost one.
+func nestedContext(fields map[string]interface{}, groups []string) map[string]interface{} {
+	for _, g := range groups {
+		next, ok := fields[g].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			fields[g] = next
+		}
+		fields = next
+	}
+	return fields
+}
+
+// pruneEmptyGroups recursively removes map values that end up empty once
+// their own children are pruned, so a WithGroup or slog.Group that never
+// receives any attrs doesn't leave a phantom {} in the output.
+func pruneEmptyGroups(fields map[string]interface{}) {
+	for k, v := range fields {
+		if m, ok := v.(map[string]interface{}); ok {
+			pruneEmptyGroups(m)
+			if len(m) == 0 {
+				delete(fields, k)
+			}
+		}
 	}
 }
 
-// addAttrToFields adds an slog.Attr to the fields map
+// addAttrToFields adds an slog.Attr to the fields map, resolving
+// slog.LogValuer values and recursively expanding slog.Group values into
+// nested maps so that, e.g., slog.Group("db", "query", "...") produces
+// fields["db"]["query"].
 func addAttrToFields(fields map[string]interface{}, attr slog.Attr) {
-	if attr.Value.Kind() == slog.KindAny {
+	attr.Value = attr.Value.Resolve()
+
+	switch attr.Value.Kind() {
+	case slog.KindGroup:
+		groupAttrs := attr.Value.Group()
+		dst := fields
+		if attr.Key != "" {
+			dst = nestedContext(fields, []string{attr.Key})
+		}
+		for _, ga := range groupAttrs {
+			addAttrToFields(dst, ga)
+		}
+	case slog.KindAny:
 		// if attr is error, convert to string
 		if err, ok := attr.Value.Any().(error); ok {
-			attr.Value = slog.StringValue(err.Error())
+			fields[attr.Key] = err.Error()
+			return
 		}
-	} else if attr.Value.Kind() == slog.KindDuration {
-		attr.Value = slog.Int64Value(attr.Value.Duration().Milliseconds())
+		fields[attr.Key] = attr.Value.Any()
+	case slog.KindDuration:
+		fields[attr.Key] = attr.Value.Duration().Milliseconds()
+	default:
+		fields[attr.Key] = attr.Value.Any()
 	}
-
-	fields[attr.Key] = attr.Value.Any()
 }
 
 // levelToGCPSeverity converts slog.Level to GCP severity string
@@ -0,0 +1,70 @@
+package glog
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestGCPHandler_WithGroupNesting(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewGCPHandler(&buf, slog.LevelInfo)
+	l := slog.New(h).WithGroup("http").With("method", "GET")
+	l.Info("x", slog.Group("db", "query", "select 1"))
+
+	var entry struct {
+		Context map[string]interface{} `json:"context"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	http, ok := entry.Context["http"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected context.http, got: %#v", entry.Context)
+	}
+	if http["method"] != "GET" {
+		t.Fatalf("expected context.http.method=GET, got: %#v", http)
+	}
+
+	db, ok := http["db"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected context.http.db, got: %#v", http)
+	}
+	if db["query"] != "select 1" {
+		t.Fatalf("expected context.http.db.query=\"select 1\", got: %#v", db)
+	}
+}
+
+func TestGCPHandler_EmptyGroupOmitted(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewGCPHandler(&buf, slog.LevelInfo)
+	slog.New(h).WithGroup("empty").Info("msg")
+
+	var entry struct {
+		Context map[string]interface{} `json:"context"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if _, ok := entry.Context["empty"]; ok {
+		t.Fatalf("expected WithGroup with no attrs to be omitted, got context: %#v", entry.Context)
+	}
+}
+
+func TestGCPHandler_EmptySlogGroupOmitted(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewGCPHandler(&buf, slog.LevelInfo)
+	slog.New(h).Info("msg", slog.Group("empty"))
+
+	var entry struct {
+		Context map[string]interface{} `json:"context"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if _, ok := entry.Context["empty"]; ok {
+		t.Fatalf("expected empty slog.Group to be omitted, got context: %#v", entry.Context)
+	}
+}
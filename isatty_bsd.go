@@ -0,0 +1,8 @@
+//go:build darwin || freebsd
+
+package glog
+
+import "syscall"
+
+// TIOCGETA differs between darwin and freebsd, so take it from syscall.
+const ioctlGetTermios = syscall.TIOCGETA
@@ -0,0 +1,5 @@
+//go:build linux
+
+package glog
+
+const ioctlGetTermios = 0x5401 // TCGETS
@@ -0,0 +1,12 @@
+//go:build !linux && !darwin && !freebsd
+
+package glog
+
+import "os"
+
+// isTerminal always reports false on platforms without a recognized ioctl
+// for fetching terminal attributes (e.g. Windows); such platforms fall back
+// to the GCP JSON handler.
+func isTerminal(f *os.File) bool {
+	return false
+}
@@ -0,0 +1,24 @@
+//go:build linux || darwin || freebsd
+
+package glog
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// isTerminal reports whether f is connected to a terminal, without
+// depending on cgo or any external terminal package. ioctlGetTermios is
+// the platform-specific request number, defined per-OS alongside this file.
+func isTerminal(f *os.File) bool {
+	var termios syscall.Termios
+	_, _, errno := syscall.Syscall6(
+		syscall.SYS_IOCTL,
+		f.Fd(),
+		ioctlGetTermios,
+		uintptr(unsafe.Pointer(&termios)),
+		0, 0, 0,
+	)
+	return errno == 0
+}
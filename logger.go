@@ -36,8 +36,15 @@ func NewLogger(level string) *slog.Logger {
 		logLevel = slog.LevelDebug
 	}
 
-	// Create handler with GCP-compatible format
-	handler := NewGCPHandler(os.Stdout, logLevel, sourceLevels)
+	// In a local dev terminal, prefer the human-readable pretty handler so
+	// stdout isn't flooded with JSON; GLOG_FORMAT=gcp forces GCP JSON
+	// output regardless (e.g. to sanity-check it locally).
+	var handler slog.Handler
+	if os.Getenv("GLOG_FORMAT") != "gcp" && isTerminal(os.Stdout) {
+		handler = NewPrettyHandler(os.Stdout, logLevel)
+	} else {
+		handler = NewGCPHandler(os.Stdout, logLevel)
+	}
 
 	logger = slog.New(handler)
 
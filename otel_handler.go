@@ -0,0 +1,212 @@
+package glog
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OTelHandler is a slog.Handler that bridges records to the OpenTelemetry
+// Logs SDK via an injected otellog.Logger, letting the same call sites that
+// feed GCPHandler also ship to an OTLP collector. Trace/span correlation is
+// pulled from the active OTel span in ctx, so it works without the
+// GCP-specific X-Cloud-Trace-Context/traceparent header path in Middleware.
+type OTelHandler struct {
+	logger otellog.Logger
+	level  slog.Level
+	attrs  []otellog.KeyValue
+	groups []string
+}
+
+// NewOTelHandler creates a handler that emits records through logger.
+func NewOTelHandler(logger otellog.Logger, level slog.Level) *OTelHandler {
+	return &OTelHandler{
+		logger: logger,
+		level:  level,
+	}
+}
+
+// Enabled reports whether the handler handles records at the given level
+func (h *OTelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+// Handle translates r into an OTel log record and emits it.
+func (h *OTelHandler) Handle(ctx context.Context, r slog.Record) error {
+	var rec otellog.Record
+	rec.SetTimestamp(r.Time)
+	rec.SetObservedTimestamp(time.Now())
+	rec.SetSeverity(levelToOTelSeverity(r.Level))
+	rec.SetSeverityText(r.Level.String())
+	rec.SetBody(otellog.StringValue(r.Message))
+
+	rec.AddAttributes(h.attrs...)
+
+	r.Attrs(func(attr slog.Attr) bool {
+		rec.AddAttributes(attrToOTelKV(attr, h.groups)...)
+		return true
+	})
+
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		rec.AddAttributes(
+			otellog.String("trace_id", sc.TraceID().String()),
+			otellog.String("span_id", sc.SpanID().String()),
+		)
+	}
+
+	h.logger.Emit(ctx, rec)
+	return nil
+}
+
+// WithAttrs returns a new handler with additional attributes
+func (h *OTelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	newAttrs := make([]otellog.KeyValue, len(h.attrs), len(h.attrs)+len(attrs))
+	copy(newAttrs, h.attrs)
+	for _, attr := range attrs {
+		newAttrs = append(newAttrs, attrToOTelKV(attr, h.groups)...)
+	}
+
+	return &OTelHandler{
+		logger: h.logger,
+		level:  h.level,
+		attrs:  newAttrs,
+		groups: h.groups,
+	}
+}
+
+// WithGroup returns a new handler with a group prefix
+func (h *OTelHandler) WithGroup(name string) slog.Handler {
+	newGroups := make([]string, len(h.groups)+1)
+	copy(newGroups, h.groups)
+	newGroups[len(h.groups)] = name
+
+	return &OTelHandler{
+		logger: h.logger,
+		level:  h.level,
+		attrs:  h.attrs,
+		groups: newGroups,
+	}
+}
+
+// attrToOTelKV converts an slog.Attr into one or more OTel KeyValues,
+// resolving slog.LogValuer and flattening slog.Group values by joining
+// group names with "." (OTel attributes have no native nesting).
+func attrToOTelKV(attr slog.Attr, groups []string) []otellog.KeyValue {
+	attr.Value = attr.Value.Resolve()
+
+	if attr.Value.Kind() == slog.KindGroup {
+		nestedGroups := groups
+		if attr.Key != "" {
+			nestedGroups = append(append([]string{}, groups...), attr.Key)
+		}
+
+		var kvs []otellog.KeyValue
+		for _, ga := range attr.Value.Group() {
+			kvs = append(kvs, attrToOTelKV(ga, nestedGroups)...)
+		}
+		return kvs
+	}
+
+	key := attr.Key
+	for i := len(groups) - 1; i >= 0; i-- {
+		key = groups[i] + "." + key
+	}
+
+	return []otellog.KeyValue{otelKeyValue(key, attr.Value)}
+}
+
+// otelKeyValue converts a resolved slog.Value into an OTel KeyValue.
+func otelKeyValue(key string, v slog.Value) otellog.KeyValue {
+	switch v.Kind() {
+	case slog.KindString:
+		return otellog.String(key, v.String())
+	case slog.KindInt64:
+		return otellog.Int64(key, v.Int64())
+	case slog.KindUint64:
+		return otellog.Int64(key, int64(v.Uint64()))
+	case slog.KindFloat64:
+		return otellog.Float64(key, v.Float64())
+	case slog.KindBool:
+		return otellog.Bool(key, v.Bool())
+	case slog.KindDuration:
+		return otellog.Int64(key, v.Duration().Milliseconds())
+	case slog.KindTime:
+		return otellog.String(key, v.Time().UTC().Format(time.RFC3339Nano))
+	default:
+		if err, ok := v.Any().(error); ok {
+			return otellog.String(key, err.Error())
+		}
+		return otellog.String(key, v.String())
+	}
+}
+
+// levelToOTelSeverity maps slog levels to OTel severity numbers.
+func levelToOTelSeverity(level slog.Level) otellog.Severity {
+	switch {
+	case level >= slog.LevelError:
+		return otellog.SeverityError
+	case level >= slog.LevelWarn:
+		return otellog.SeverityWarn
+	case level >= slog.LevelInfo:
+		return otellog.SeverityInfo
+	default:
+		return otellog.SeverityDebug
+	}
+}
+
+// teeHandler fans a record out to multiple slog.Handlers.
+type teeHandler struct {
+	handlers []slog.Handler
+}
+
+// NewTee returns a slog.Handler that dispatches every record to each of
+// handlers, e.g. to keep GCPHandler writing to stdout while also shipping
+// records to an OTLP collector via OTelHandler from the same call site.
+func NewTee(handlers ...slog.Handler) slog.Handler {
+	return &teeHandler{handlers: handlers}
+}
+
+// Enabled reports true if any underlying handler would handle the record.
+func (t *teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range t.handlers {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle dispatches r to every underlying handler, returning the first error.
+func (t *teeHandler) Handle(ctx context.Context, r slog.Record) error {
+	var firstErr error
+	for _, h := range t.handlers {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, r.Clone()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// WithAttrs propagates the attrs to every underlying handler.
+func (t *teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	newHandlers := make([]slog.Handler, len(t.handlers))
+	for i, h := range t.handlers {
+		newHandlers[i] = h.WithAttrs(attrs)
+	}
+	return &teeHandler{handlers: newHandlers}
+}
+
+// WithGroup propagates the group to every underlying handler.
+func (t *teeHandler) WithGroup(name string) slog.Handler {
+	newHandlers := make([]slog.Handler, len(t.handlers))
+	for i, h := range t.handlers {
+		newHandlers[i] = h.WithGroup(name)
+	}
+	return &teeHandler{handlers: newHandlers}
+}
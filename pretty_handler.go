@@ -0,0 +1,198 @@
+package glog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ANSI color codes used by PrettyHandler, one per severity.
+const (
+	ansiReset  = "\x1b[0m"
+	ansiGrey   = "\x1b[90m"
+	ansiCyan   = "\x1b[36m"
+	ansiYellow = "\x1b[33m"
+	ansiRed    = "\x1b[31m"
+)
+
+// moduleRoot is this package's own directory, used to render source
+// locations relative to the module root when the caller lives in it.
+var moduleRoot = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file) + string(filepath.Separator)
+}()
+
+// PrettyHandler is a slog.Handler that renders human-readable, colorized
+// log lines for local development terminals.
+type PrettyHandler struct {
+	mu     *sync.Mutex
+	w      io.Writer
+	level  slog.Level
+	attrs  []attrGroup
+	groups []string
+}
+
+// NewPrettyHandler creates a handler that renders
+// "HH:MM:SS.mmm LEVEL msg file:line key=value ..." lines with ANSI color per
+// severity; file:line is omitted when the record has no PC.
+// It's intended for local development; production deployments should use
+// NewGCPHandler.
+func NewPrettyHandler(w io.Writer, level slog.Level) slog.Handler {
+	return &PrettyHandler{
+		mu:    &sync.Mutex{},
+		w:     w,
+		level: level,
+	}
+}
+
+// Enabled reports whether the handler handles records at the given level
+func (h *PrettyHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+// Handle formats and writes a log record
+func (h *PrettyHandler) Handle(ctx context.Context, r slog.Record) error {
+	var b strings.Builder
+
+	b.WriteString(r.Time.Format("15:04:05.000"))
+	b.WriteByte(' ')
+	b.WriteString(colorForLevel(r.Level))
+	fmt.Fprintf(&b, "%-5s", levelAbbrev(r.Level))
+	b.WriteString(ansiReset)
+	b.WriteByte(' ')
+	b.WriteString(r.Message)
+
+	if r.PC != 0 {
+		fs := runtime.CallersFrames([]uintptr{r.PC})
+		f, _ := fs.Next()
+		fmt.Fprintf(&b, " %s%s:%d%s", ansiGrey, relSource(f.File), f.Line, ansiReset)
+	}
+
+	fields := map[string]interface{}{}
+	for _, ag := range h.attrs {
+		dst := nestedContext(fields, ag.groups)
+		for _, attr := range ag.attrs {
+			addAttrToFields(dst, attr)
+		}
+	}
+
+	dst := nestedContext(fields, h.groups)
+	r.Attrs(func(attr slog.Attr) bool {
+		addAttrToFields(dst, attr)
+		return true
+	})
+
+	// drop any group that ended up with nothing in it, e.g. WithGroup with
+	// no attrs, so it doesn't print a dangling "name:" line
+	pruneEmptyGroups(fields)
+
+	writePrettyFields(&b, fields, 0)
+
+	b.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+// WithAttrs returns a new handler with additional attributes, tagged with
+// the handler's current group path so Handle can nest them correctly.
+func (h *PrettyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	newAttrs := make([]attrGroup, len(h.attrs)+1)
+	copy(newAttrs, h.attrs)
+	newAttrs[len(h.attrs)] = attrGroup{groups: h.groups, attrs: attrs}
+
+	return &PrettyHandler{
+		mu:     h.mu,
+		w:      h.w,
+		level:  h.level,
+		attrs:  newAttrs,
+		groups: h.groups,
+	}
+}
+
+// WithGroup returns a new handler with a group prefix
+func (h *PrettyHandler) WithGroup(name string) slog.Handler {
+	newGroups := make([]string, len(h.groups)+1)
+	copy(newGroups, h.groups)
+	newGroups[len(h.groups)] = name
+
+	return &PrettyHandler{
+		mu:     h.mu,
+		w:      h.w,
+		level:  h.level,
+		attrs:  h.attrs,
+		groups: newGroups,
+	}
+}
+
+// writePrettyFields writes the flat values as "key=value" pairs on the
+// current line, then each nested group on a new line indented one step
+// deeper, as "key:" followed by that group's own flat pairs, with its
+// subgroups indented further below it.
+// fields is expected to already be nested at the correct depth (see
+// nestedContext in gcp_handler.go), so no group wrapping happens here.
+func writePrettyFields(b *strings.Builder, fields map[string]interface{}, indent int) {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if _, ok := fields[k].(map[string]interface{}); !ok {
+			b.WriteByte(' ')
+			fmt.Fprintf(b, "%s=%v", k, fields[k])
+		}
+	}
+	for _, k := range keys {
+		if v, ok := fields[k].(map[string]interface{}); ok {
+			b.WriteByte('\n')
+			b.WriteString(strings.Repeat("  ", indent+1))
+			fmt.Fprintf(b, "%s:", k)
+			writePrettyFields(b, v, indent+1)
+		}
+	}
+}
+
+// relSource renders file relative to this module's root when the caller
+// lives in it, falling back to the basename otherwise.
+func relSource(file string) string {
+	if rel := strings.TrimPrefix(file, moduleRoot); rel != file {
+		return rel
+	}
+	return filepath.Base(file)
+}
+
+func colorForLevel(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return ansiRed
+	case level >= slog.LevelWarn:
+		return ansiYellow
+	case level >= slog.LevelInfo:
+		return ansiCyan
+	default:
+		return ansiGrey
+	}
+}
+
+func levelAbbrev(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return "ERROR"
+	case level >= slog.LevelWarn:
+		return "WARN"
+	case level >= slog.LevelInfo:
+		return "INFO"
+	default:
+		return "DEBUG"
+	}
+}
@@ -0,0 +1,18 @@
+package glog
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestPrettyHandler_EmptyGroupOmitted(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewPrettyHandler(&buf, slog.LevelInfo)
+	slog.New(h).WithGroup("empty").Info("msg")
+
+	if strings.Contains(buf.String(), "empty:") {
+		t.Fatalf("expected WithGroup with no attrs to be omitted, got: %s", buf.String())
+	}
+}
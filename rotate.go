@@ -0,0 +1,78 @@
+package glog
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// Reopener is implemented by writers that can close and reopen their
+// underlying file descriptor, e.g. in response to log rotation.
+type Reopener interface {
+	Reopen() error
+}
+
+// ReopenableFile is an io.Writer backed by a file opened by path, guarded
+// by a mutex so writes and reopens can't interleave. It implements
+// Reopener, which lets it be used with InstallSIGHUPReopen to integrate
+// with logrotate's copytruncate/postrotate workflow without dropping log
+// lines or restarting the process.
+type ReopenableFile struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+}
+
+// NewReopenableFile opens path for appending (creating it if necessary)
+// and returns a ReopenableFile writing to it.
+func NewReopenableFile(path string) (*ReopenableFile, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &ReopenableFile{path: path, f: f}, nil
+}
+
+// Write implements io.Writer.
+func (r *ReopenableFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.f.Write(p)
+}
+
+// Reopen closes the current file and reopens path, atomically swapping in
+// the new descriptor. This is what lets a `logrotate` postrotate step
+// (which renames the old file out from under us) hand the process a fresh
+// file without it needing to restart.
+func (r *ReopenableFile) Reopen() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f, err := os.OpenFile(r.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	old := r.f
+	r.f = f
+	return old.Close()
+}
+
+// InstallSIGHUPReopen spawns a goroutine that calls Reopen on each of the
+// given writers whenever the process receives SIGHUP. This is the standard
+// hook point for `logrotate`'s copytruncate/postrotate workflow on
+// long-lived services that write logs to disk. Any Reopen-capable writer
+// can be registered, not just *ReopenableFile.
+func InstallSIGHUPReopen(writers ...Reopener) {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+
+	go func() {
+		for range sighup {
+			for _, w := range writers {
+				_ = w.Reopen()
+			}
+		}
+	}()
+}
@@ -0,0 +1,101 @@
+package glog
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// TraceContext carries the Cloud Trace identifiers for a single request so
+// that log entries emitted while handling it can be correlated with the
+// corresponding trace in the GCP Log Explorer.
+type TraceContext struct {
+	Trace   string
+	SpanID  string
+	Sampled bool
+}
+
+type traceContextKey string
+
+const traceKey traceContextKey = "trace"
+
+// WithTrace stashes a TraceContext in ctx for later retrieval by GCPHandler.
+func WithTrace(ctx context.Context, tc TraceContext) context.Context {
+	return context.WithValue(ctx, traceKey, tc)
+}
+
+// traceFromContext retrieves the TraceContext stashed by WithTrace, if any.
+func traceFromContext(ctx context.Context) (TraceContext, bool) {
+	tc, ok := ctx.Value(traceKey).(TraceContext)
+	return tc, ok
+}
+
+// Middleware returns an http.Handler middleware that extracts trace/span
+// identifiers from the incoming request (either the GCP
+// "X-Cloud-Trace-Context" header or a W3C "traceparent" header) and makes
+// them available via WithTrace, so that GCPHandler.Handle can lift the
+// correlation into the dedicated logging.googleapis.com/trace fields for
+// any glog.*Context call made with the request's context.
+func Middleware(projectID string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			if tc, ok := parseTraceHeaders(r); ok {
+				tc.Trace = "projects/" + projectID + "/traces/" + tc.Trace
+				ctx = WithTrace(ctx, tc)
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// parseTraceHeaders extracts a TraceContext from either the
+// "X-Cloud-Trace-Context" or "traceparent" request header. The returned
+// TraceContext.Trace is the bare trace ID, not yet project-qualified.
+func parseTraceHeaders(r *http.Request) (TraceContext, bool) {
+	if h := r.Header.Get("X-Cloud-Trace-Context"); h != "" {
+		return parseCloudTraceContext(h)
+	}
+	if h := r.Header.Get("traceparent"); h != "" {
+		return parseTraceparent(h)
+	}
+	return TraceContext{}, false
+}
+
+// parseCloudTraceContext parses "TRACE_ID/SPAN_ID;o=OPTIONS".
+func parseCloudTraceContext(h string) (TraceContext, bool) {
+	traceAndRest, opts, _ := strings.Cut(h, ";")
+	traceID, spanID, ok := strings.Cut(traceAndRest, "/")
+	if !ok || traceID == "" {
+		return TraceContext{}, false
+	}
+
+	sampled := false
+	if _, val, ok := strings.Cut(opts, "o="); ok {
+		sampled = val == "1"
+	}
+
+	return TraceContext{Trace: traceID, SpanID: spanID, Sampled: sampled}, true
+}
+
+// parseTraceparent parses the W3C "version-traceid-spanid-flags" format.
+func parseTraceparent(h string) (TraceContext, bool) {
+	parts := strings.Split(h, "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return TraceContext{}, false
+	}
+
+	flags, err := strconv.ParseUint(parts[3], 16, 8)
+	if err != nil {
+		return TraceContext{}, false
+	}
+
+	return TraceContext{
+		Trace:   parts[1],
+		SpanID:  parts[2],
+		Sampled: flags&0x1 == 1,
+	}, true
+}
@@ -0,0 +1,151 @@
+package glog
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseCloudTraceContext(t *testing.T) {
+	cases := []struct {
+		name   string
+		header string
+		want   TraceContext
+		wantOK bool
+	}{
+		{
+			name:   "valid sampled",
+			header: "105445aa7843bc8bf206b12000100000/1;o=1",
+			want:   TraceContext{Trace: "105445aa7843bc8bf206b12000100000", SpanID: "1", Sampled: true},
+			wantOK: true,
+		},
+		{
+			name:   "valid not sampled",
+			header: "105445aa7843bc8bf206b12000100000/1;o=0",
+			want:   TraceContext{Trace: "105445aa7843bc8bf206b12000100000", SpanID: "1", Sampled: false},
+			wantOK: true,
+		},
+		{
+			name:   "no options suffix",
+			header: "105445aa7843bc8bf206b12000100000/1",
+			want:   TraceContext{Trace: "105445aa7843bc8bf206b12000100000", SpanID: "1", Sampled: false},
+			wantOK: true,
+		},
+		{
+			name:   "missing span id",
+			header: "105445aa7843bc8bf206b12000100000",
+			wantOK: false,
+		},
+		{
+			name:   "empty header",
+			header: "",
+			wantOK: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := parseCloudTraceContext(tc.header)
+			if ok != tc.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tc.wantOK)
+			}
+			if ok && got != tc.want {
+				t.Fatalf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseTraceparent(t *testing.T) {
+	cases := []struct {
+		name   string
+		header string
+		want   TraceContext
+		wantOK bool
+	}{
+		{
+			name:   "valid sampled",
+			header: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			want:   TraceContext{Trace: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7", Sampled: true},
+			wantOK: true,
+		},
+		{
+			name:   "valid not sampled",
+			header: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+			want:   TraceContext{Trace: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7", Sampled: false},
+			wantOK: true,
+		},
+		{
+			name:   "wrong segment count",
+			header: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+			wantOK: false,
+		},
+		{
+			name:   "overlong trace id",
+			header: "00-4bf92f3577b34da6a3ce929d0e0e4736ff-00f067aa0ba902b7-01",
+			wantOK: false,
+		},
+		{
+			name:   "short span id",
+			header: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902-01",
+			wantOK: false,
+		},
+		{
+			name:   "non-hex flags",
+			header: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-zz",
+			wantOK: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := parseTraceparent(tc.header)
+			if ok != tc.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tc.wantOK)
+			}
+			if ok && got != tc.want {
+				t.Fatalf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var gotTrace TraceContext
+	var gotOK bool
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotTrace, gotOK = traceFromContext(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Cloud-Trace-Context", "105445aa7843bc8bf206b12000100000/1;o=1")
+
+	Middleware("my-project")(next).ServeHTTP(httptest.NewRecorder(), req)
+
+	if !gotOK {
+		t.Fatal("expected TraceContext to be stashed in the request context")
+	}
+	wantTrace := "projects/my-project/traces/105445aa7843bc8bf206b12000100000"
+	if gotTrace.Trace != wantTrace {
+		t.Fatalf("Trace = %q, want %q", gotTrace.Trace, wantTrace)
+	}
+	if gotTrace.SpanID != "1" || !gotTrace.Sampled {
+		t.Fatalf("unexpected trace context: %+v", gotTrace)
+	}
+}
+
+func TestMiddleware_NoTraceHeader(t *testing.T) {
+	var gotOK bool
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, gotOK = traceFromContext(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	Middleware("my-project")(next).ServeHTTP(httptest.NewRecorder(), req)
+
+	if gotOK {
+		t.Fatal("expected no TraceContext to be stashed when no trace header is present")
+	}
+}
@@ -0,0 +1,114 @@
+package glog
+
+import (
+	"fmt"
+	"log/slog"
+	"path"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// vmoduleSpec is a single pattern=level rule, matched against a frame's
+// file and function name in declaration order.
+type vmoduleSpec struct {
+	pattern string
+	level   slog.Level
+}
+
+var (
+	vmoduleMu    sync.RWMutex
+	vmoduleSpecs []vmoduleSpec
+)
+
+// SetVModule configures per-file/package/function log verbosity from a
+// Geth-style vmodule spec: a comma-separated list of pattern=level pairs,
+// e.g. "github.com/acme/foo/*=debug,server.go=info,*/handlers/*=warn".
+// Patterns are matched in declaration order against both the caller's
+// file path and fully-qualified function name using path.Match semantics;
+// the first match wins. Passing an empty spec clears all rules.
+func SetVModule(spec string) error {
+	if spec == "" {
+		vmoduleMu.Lock()
+		vmoduleSpecs = nil
+		vmoduleMu.Unlock()
+		return nil
+	}
+
+	parts := strings.Split(spec, ",")
+	specs := make([]vmoduleSpec, 0, len(parts))
+	for _, part := range parts {
+		pattern, levelStr, ok := strings.Cut(part, "=")
+		if !ok {
+			return fmt.Errorf("glog: invalid vmodule spec entry %q, expected pattern=level", part)
+		}
+
+		level, err := parseLevel(levelStr)
+		if err != nil {
+			return err
+		}
+
+		if _, err := path.Match(pattern, ""); err != nil {
+			return err
+		}
+
+		specs = append(specs, vmoduleSpec{pattern: pattern, level: level})
+	}
+
+	vmoduleMu.Lock()
+	vmoduleSpecs = specs
+	vmoduleMu.Unlock()
+	return nil
+}
+
+// SetPackageLevel adds a single pattern=level rule, e.g.
+// SetPackageLevel("github.com/acme/foo/*", slog.LevelDebug). Unlike
+// SetVModule it does not replace the current rules: the new rule is appended
+// after them, so an earlier matching rule still wins.
+func SetPackageLevel(pkg string, level slog.Level) {
+	vmoduleMu.Lock()
+	vmoduleSpecs = append(vmoduleSpecs, vmoduleSpec{pattern: pkg, level: level})
+	vmoduleMu.Unlock()
+}
+
+// vmoduleActive reports whether any vmodule pattern is currently set.
+func vmoduleActive() bool {
+	vmoduleMu.RLock()
+	defer vmoduleMu.RUnlock()
+	return len(vmoduleSpecs) > 0
+}
+
+// matchVModule walks the configured vmodule patterns in declaration order
+// and returns the level of the first one matching the given frame's file
+// or function name.
+func matchVModule(f runtime.Frame) (slog.Level, bool) {
+	vmoduleMu.RLock()
+	defer vmoduleMu.RUnlock()
+
+	for _, spec := range vmoduleSpecs {
+		if matched, _ := path.Match(spec.pattern, f.File); matched {
+			return spec.level, true
+		}
+		if matched, _ := path.Match(spec.pattern, f.Function); matched {
+			return spec.level, true
+		}
+	}
+	return 0, false
+}
+
+// parseLevel parses the same level names accepted by NewLogger
+// ("debug", "info", "warn", "error").
+func parseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("glog: unknown vmodule level %q", s)
+	}
+}
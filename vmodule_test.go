@@ -0,0 +1,22 @@
+package glog
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+)
+
+// BenchmarkGCPHandler_NoVModule exercises Handle's fast path, taken when no
+// vmodule patterns are configured, to guard against regressions that would
+// make the common no-vmodule case pay for vmodule frame-matching overhead.
+func BenchmarkGCPHandler_NoVModule(b *testing.B) {
+	SetVModule("")
+
+	h := NewGCPHandler(io.Discard, slog.LevelInfo)
+	l := slog.New(h)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info("benchmark message", "key", "value")
+	}
+}